@@ -0,0 +1,576 @@
+package jpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sethjback/jpatch/jpatcherror"
+)
+
+// ErrTestFailed is returned by Apply/ApplyToValue when a "test" operation's
+// value does not match the value found at its path.
+var ErrTestFailed = jpatcherror.ErrTestFailed
+
+// ApplyOption configures Apply and ApplyToValue.
+type ApplyOption func(*applyOptions)
+
+type applyOptions struct {
+	supportNegativeIndices   bool
+	accumulatedCopySizeLimit int64
+	ensurePathExistsOnAdd    bool
+}
+
+// WithSupportNegativeIndices allows array indices (in "path" and "from") to be
+// negative, counting back from the end of the array, e.g. "-1" is the last element.
+func WithSupportNegativeIndices(support bool) ApplyOption {
+	return func(o *applyOptions) { o.supportNegativeIndices = support }
+}
+
+// WithAccumulatedCopySizeLimit bounds the total number of bytes (as measured by
+// their JSON encoding) that "copy" operations within a single Apply/ApplyToValue
+// call may add to the document. A negative limit (the default) disables the check.
+func WithAccumulatedCopySizeLimit(limit int64) ApplyOption {
+	return func(o *applyOptions) { o.accumulatedCopySizeLimit = limit }
+}
+
+// WithEnsurePathExistsOnAdd causes "add" operations to create any missing
+// intermediate objects along their path instead of failing.
+func WithEnsurePathExistsOnAdd(ensure bool) ApplyOption {
+	return func(o *applyOptions) { o.ensurePathExistsOnAdd = ensure }
+}
+
+// Apply applies patches to a JSON document and returns the resulting document.
+// Object key order is preserved for keys untouched by the patch set. If any
+// operation fails, Apply returns an error and doc is left unaffected.
+func Apply(doc []byte, patches []Patch, opts ...ApplyOption) ([]byte, error) {
+	v, err := decodeJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jpatch: decoding document: %w", err)
+	}
+
+	result, err := applyPatches(v, patches, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeJSON(result)
+}
+
+// ApplyToValue applies patches to an in-memory value (as decoded by Apply, or a
+// plain map[string]interface{}/[]interface{} tree from encoding/json) and returns
+// the resulting value. v itself is never modified; on error the returned value is
+// nil and the error describes which operation failed.
+func ApplyToValue(v interface{}, patches []Patch, opts ...ApplyOption) (interface{}, error) {
+	return applyPatches(v, patches, opts...)
+}
+
+func applyPatches(v interface{}, patches []Patch, opts ...ApplyOption) (interface{}, error) {
+	o := &applyOptions{accumulatedCopySizeLimit: -1}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// Work against a private deep copy so a failure partway through leaves the
+	// caller's value (and, for Apply, the original document bytes) untouched.
+	root := deepCopy(v)
+	var copiedBytes int64
+
+	for i, p := range patches {
+		next, err := applyOne(root, p, o, &copiedBytes)
+		if err != nil {
+			return nil, fmt.Errorf("jpatch: patch %d (%s %s): %w", i, p.Op, p.Path, err)
+		}
+		root = next
+	}
+
+	return root, nil
+}
+
+func applyOne(root interface{}, p Patch, o *applyOptions, copiedBytes *int64) (interface{}, error) {
+	switch p.Op {
+	case Add:
+		return applyAdd(root, p.Path, p.Value, o)
+	case Remove:
+		return applyRemove(root, p.Path, o)
+	case Replace:
+		return applyReplace(root, p.Path, p.Value, o)
+	case Move:
+		v, err := applyGet(root, p.From, o)
+		if err != nil {
+			return nil, err
+		}
+		root, err = applyRemove(root, p.From, o)
+		if err != nil {
+			return nil, err
+		}
+		return applyAdd(root, p.Path, v, o)
+	case Copy:
+		v, err := applyGet(root, p.From, o)
+		if err != nil {
+			return nil, err
+		}
+		if o.accumulatedCopySizeLimit >= 0 {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			*copiedBytes += int64(len(b))
+			if *copiedBytes > o.accumulatedCopySizeLimit {
+				return nil, fmt.Errorf("accumulated copy size exceeds limit of %d bytes", o.accumulatedCopySizeLimit)
+			}
+		}
+		return applyAdd(root, p.Path, v, o)
+	case Test:
+		v, err := applyGet(root, p.Path, o)
+		if err != nil {
+			return nil, err
+		}
+		if !deepEqual(v, p.Value) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", p.Op)
+	}
+}
+
+func applyAdd(root interface{}, path string, value interface{}, o *applyOptions) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return deepCopy(value), nil
+	}
+
+	parent, err := navigate(root, tokens[:len(tokens)-1], o)
+	if err != nil && o.ensurePathExistsOnAdd {
+		parent, err = ensurePath(root, tokens[:len(tokens)-1], o)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch c := parent.(type) {
+	case *orderedMap:
+		c.Set(last, deepCopy(value))
+	case *arrayValue:
+		if last == "-" {
+			c.items = append(c.items, deepCopy(value))
+			break
+		}
+		idx, err := arrayIndex(last, len(c.items), o.supportNegativeIndices, true)
+		if err != nil {
+			return nil, err
+		}
+		c.items = append(c.items, nil)
+		copy(c.items[idx+1:], c.items[idx:])
+		c.items[idx] = deepCopy(value)
+	default:
+		return nil, fmt.Errorf("path %q does not resolve to an object or array", path)
+	}
+
+	return root, nil
+}
+
+func applyRemove(root interface{}, path string, o *applyOptions) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot remove the document root")
+	}
+
+	parent, err := navigate(root, tokens[:len(tokens)-1], o)
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch c := parent.(type) {
+	case *orderedMap:
+		if _, ok := c.Get(last); !ok {
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+		c.Delete(last)
+	case *arrayValue:
+		idx, err := arrayIndex(last, len(c.items), o.supportNegativeIndices, false)
+		if err != nil {
+			return nil, err
+		}
+		c.items = append(c.items[:idx], c.items[idx+1:]...)
+	default:
+		return nil, fmt.Errorf("path %q does not resolve to an object or array", path)
+	}
+
+	return root, nil
+}
+
+func applyReplace(root interface{}, path string, value interface{}, o *applyOptions) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return deepCopy(value), nil
+	}
+
+	parent, err := navigate(root, tokens[:len(tokens)-1], o)
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch c := parent.(type) {
+	case *orderedMap:
+		if _, ok := c.Get(last); !ok {
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+		c.Set(last, deepCopy(value))
+	case *arrayValue:
+		idx, err := arrayIndex(last, len(c.items), o.supportNegativeIndices, false)
+		if err != nil {
+			return nil, err
+		}
+		c.items[idx] = deepCopy(value)
+	default:
+		return nil, fmt.Errorf("path %q does not resolve to an object or array", path)
+	}
+
+	return root, nil
+}
+
+func applyGet(root interface{}, path string, o *applyOptions) (interface{}, error) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, err
+	}
+	return navigate(root, tokens, o)
+}
+
+// ensurePath walks tokens from root, creating missing intermediate objects as it goes.
+func ensurePath(root interface{}, tokens []string, o *applyOptions) (interface{}, error) {
+	cur := root
+	for _, tok := range tokens {
+		switch c := cur.(type) {
+		case *orderedMap:
+			v, ok := c.Get(tok)
+			if !ok {
+				v = newOrderedMap()
+				c.Set(tok, v)
+			}
+			cur = v
+		case *arrayValue:
+			idx, err := arrayIndex(tok, len(c.items), o.supportNegativeIndices, false)
+			if err != nil {
+				return nil, err
+			}
+			if c.items[idx] == nil {
+				c.items[idx] = newOrderedMap()
+			}
+			cur = c.items[idx]
+		default:
+			return nil, fmt.Errorf("cannot create path through a scalar value")
+		}
+	}
+	return cur, nil
+}
+
+// navigate walks tokens from root and returns the value found, or an error if any
+// segment does not resolve.
+func navigate(root interface{}, tokens []string, o *applyOptions) (interface{}, error) {
+	cur := root
+	for _, tok := range tokens {
+		switch c := cur.(type) {
+		case *orderedMap:
+			v, ok := c.Get(tok)
+			if !ok {
+				return nil, fmt.Errorf("path segment %q not found", tok)
+			}
+			cur = v
+		case *arrayValue:
+			idx, err := arrayIndex(tok, len(c.items), o.supportNegativeIndices, false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c.items[idx]
+		default:
+			return nil, fmt.Errorf("cannot traverse into a scalar value at %q", tok)
+		}
+	}
+	return cur, nil
+}
+
+// arrayIndex resolves a path token to an array index. allowEnd permits the index
+// to equal length (used by "add", which may insert at the end of the array).
+func arrayIndex(tok string, length int, allowNegative, allowEnd bool) (int, error) {
+	i, err := strconv.Atoi(tok)
+	if err != nil {
+		return -1, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	if i < 0 {
+		if !allowNegative {
+			return -1, fmt.Errorf("negative array index %q not supported", tok)
+		}
+		i += length
+	}
+
+	max := length
+	if allowEnd {
+		max++
+	}
+	if i < 0 || i >= max {
+		return -1, fmt.Errorf("array index %q out of bounds", tok)
+	}
+
+	return i, nil
+}
+
+// pointerTokens splits an RFC 6901 JSON pointer into its unescaped reference tokens.
+func pointerTokens(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid path %q: must begin with /", path)
+	}
+
+	raw := strings.Split(path, "/")[1:]
+	tokens := make([]string, len(raw))
+	for i, t := range raw {
+		tokens[i] = unescapeToken(t)
+	}
+	return tokens, nil
+}
+
+func unescapeToken(t string) string {
+	t = strings.ReplaceAll(t, "~1", "/")
+	t = strings.ReplaceAll(t, "~0", "~")
+	return t
+}
+
+func escapeToken(t string) string {
+	t = strings.ReplaceAll(t, "~", "~0")
+	t = strings.ReplaceAll(t, "/", "~1")
+	return t
+}
+
+// deepCopy recursively copies a decoded JSON value, normalizing plain
+// map[string]interface{}/[]interface{} trees (as produced by encoding/json, e.g.
+// from a Patch.Value) into the orderedMap/arrayValue representation used internally.
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case *orderedMap:
+		m := newOrderedMap()
+		for _, k := range t.keys {
+			m.Set(k, deepCopy(t.values[k]))
+		}
+		return m
+	case *arrayValue:
+		items := make([]interface{}, len(t.items))
+		for i, it := range t.items {
+			items[i] = deepCopy(it)
+		}
+		return &arrayValue{items: items}
+	case map[string]interface{}:
+		m := newOrderedMap()
+		for k, vv := range t {
+			m.Set(k, deepCopy(vv))
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(t))
+		for i, it := range t {
+			items[i] = deepCopy(it)
+		}
+		return &arrayValue{items: items}
+	default:
+		return v
+	}
+}
+
+// deepEqual reports whether a and b represent the same JSON value, ignoring
+// differences between the internal ordered representation and plain
+// map[string]interface{}/[]interface{} trees, and between json.Number and float64.
+func deepEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(canonicalize(a), canonicalize(b))
+}
+
+func canonicalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case *orderedMap:
+		m := make(map[string]interface{}, len(t.keys))
+		for _, k := range t.keys {
+			m[k] = canonicalize(t.values[k])
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, vv := range t {
+			m[k] = canonicalize(vv)
+		}
+		return m
+	case *arrayValue:
+		arr := make([]interface{}, len(t.items))
+		for i, it := range t.items {
+			arr[i] = canonicalize(it)
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(t))
+		for i, it := range t {
+			arr[i] = canonicalize(it)
+		}
+		return arr
+	case json.Number:
+		f, _ := t.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// orderedMap is a JSON object that remembers the order in which its keys were
+// first set, so documents round-trip through Apply without reshuffling keys
+// that were never touched by a patch.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+func (m *orderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) Delete(key string) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// MarshalJSON writes the object back out with keys in their original order.
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// arrayValue is a JSON array. It is boxed in a pointer, like orderedMap, so that
+// insertions and deletions performed deep within a document are visible to every
+// reference to the containing array without having to thread the new slice header
+// back up through its ancestors.
+type arrayValue struct {
+	items []interface{}
+}
+
+func (a *arrayValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.items)
+}
+
+// decodeJSON parses a JSON document into orderedMap/arrayValue/scalar values,
+// preserving object key order and using json.Number for numbers so they round-trip
+// without losing precision.
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return decodeToken(dec, tok)
+}
+
+func decodeToken(dec *json.Decoder, tok json.Token) (interface{}, error) {
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		m := newOrderedMap()
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(keyTok.(string), val)
+		}
+		if _, err := dec.Token(); err != nil { // consume '}'
+			return nil, err
+		}
+		return m, nil
+	case '[':
+		arr := &arrayValue{items: []interface{}{}}
+		for dec.More() {
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr.items = append(arr.items, val)
+		}
+		if _, err := dec.Token(); err != nil { // consume ']'
+			return nil, err
+		}
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %q", delim)
+	}
+}
+
+// encodeJSON marshals a value produced by decodeJSON/ApplyToValue back to JSON.
+func encodeJSON(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
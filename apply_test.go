@@ -0,0 +1,119 @@
+package jpatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyAddReplaceRemove(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`{"name":"alice","tags":["a","b"]}`)
+
+	out, err := Apply(doc, []Patch{
+		{Op: Replace, Path: "/name", Value: "bob"},
+		{Op: Add, Path: "/tags/-", Value: "c"},
+		{Op: Remove, Path: "/tags/0"},
+		{Op: Add, Path: "/age", Value: 30},
+	})
+
+	assert.Nil(err)
+	assert.JSONEq(`{"name":"bob","tags":["b","c"],"age":30}`, string(out))
+}
+
+func TestApplyPreservesKeyOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`{"z":1,"a":2,"m":3}`)
+
+	out, err := Apply(doc, []Patch{{Op: Replace, Path: "/a", Value: 99}})
+
+	assert.Nil(err)
+	assert.Equal(`{"z":1,"a":99,"m":3}`, string(out))
+}
+
+func TestApplyMoveAndCopy(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`{"foo":{"bar":1},"baz":null}`)
+
+	out, err := Apply(doc, []Patch{
+		{Op: Copy, From: "/foo", Path: "/qux"},
+		{Op: Move, From: "/baz", Path: "/foo/baz"},
+	})
+
+	assert.Nil(err)
+	assert.JSONEq(`{"foo":{"bar":1,"baz":null},"qux":{"bar":1}}`, string(out))
+}
+
+func TestApplyTestOp(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`{"a":1}`)
+
+	_, err := Apply(doc, []Patch{{Op: Test, Path: "/a", Value: float64(1)}})
+	assert.Nil(err)
+
+	_, err = Apply(doc, []Patch{{Op: Test, Path: "/a", Value: float64(2)}})
+	if assert.NotNil(err) {
+		assert.True(errors.Is(err, ErrTestFailed))
+	}
+}
+
+func TestApplyRollsBackOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`{"a":1}`)
+
+	_, err := Apply(doc, []Patch{
+		{Op: Add, Path: "/b", Value: 2},
+		{Op: Remove, Path: "/missing"},
+	})
+
+	assert.NotNil(err)
+	assert.Equal(`{"a":1}`, string(doc))
+}
+
+func TestApplyNegativeIndices(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`[1,2,3]`)
+
+	_, err := Apply(doc, []Patch{{Op: Remove, Path: "/-1"}})
+	assert.NotNil(err)
+
+	out, err := Apply(doc, []Patch{{Op: Remove, Path: "/-1"}}, WithSupportNegativeIndices(true))
+	assert.Nil(err)
+	assert.JSONEq(`[1,2]`, string(out))
+}
+
+func TestApplyNegativeIndicesInTestAndFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`{"a":[1,2,3]}`)
+
+	_, err := Apply(doc, []Patch{{Op: Test, Path: "/a/-1", Value: float64(3)}})
+	assert.NotNil(err)
+
+	_, err = Apply(doc, []Patch{{Op: Test, Path: "/a/-1", Value: float64(3)}}, WithSupportNegativeIndices(true))
+	assert.Nil(err)
+
+	out, err := Apply(doc, []Patch{{Op: Move, From: "/a/-1", Path: "/b"}}, WithSupportNegativeIndices(true))
+	assert.Nil(err)
+	assert.JSONEq(`{"a":[1,2],"b":3}`, string(out))
+}
+
+func TestApplyEnsurePathExistsOnAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := []byte(`{}`)
+
+	_, err := Apply(doc, []Patch{{Op: Add, Path: "/a/b/c", Value: 1}})
+	assert.NotNil(err)
+
+	out, err := Apply(doc, []Patch{{Op: Add, Path: "/a/b/c", Value: 1}}, WithEnsurePathExistsOnAdd(true))
+	assert.Nil(err)
+	assert.JSONEq(`{"a":{"b":{"c":1}}}`, string(out))
+}
@@ -0,0 +1,252 @@
+package jpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// DiffOption configures Diff and DiffJSON.
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	detectMoves bool
+	ignorePaths map[string]bool
+}
+
+// WithDetectMoves causes Diff to collapse a remove/add pair of object members
+// into a single "move" operation whenever the removed and added values are
+// identical, instead of emitting them as two independent operations. Array
+// element removes/adds are never collapsed.
+func WithDetectMoves(detect bool) DiffOption {
+	return func(o *diffOptions) { o.detectMoves = detect }
+}
+
+// WithIgnorePaths excludes the given pointer paths (and everything beneath them)
+// from the generated patch, e.g. to skip timestamp or etag fields that change on
+// every write but aren't meaningful to diff. Paths are matched against object
+// members only: array elements are compared whole, so a path that addresses an
+// array element, or anything inside one, is not excluded.
+func WithIgnorePaths(paths []string) DiffOption {
+	return func(o *diffOptions) {
+		o.ignorePaths = make(map[string]bool, len(paths))
+		for _, p := range paths {
+			o.ignorePaths[p] = true
+		}
+	}
+}
+
+// diffOp pairs a generated Patch with the value it touched, so WithDetectMoves
+// can later recognize a remove/add pair that moved the same value. arrayElement
+// marks a remove/add whose path is a positional array index rather than a
+// stable object member name: those paths shift as sibling elements are
+// removed/added, so they can never be safely collapsed into a "move".
+type diffOp struct {
+	patch        Patch
+	value        interface{}
+	arrayElement bool
+}
+
+// Diff computes a minimal RFC 6902 patch that transforms a into b.
+func Diff(a, b interface{}, opts ...DiffOption) ([]Patch, error) {
+	o := &diffOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var ops []diffOp
+	diffValues("", canonicalize(a), canonicalize(b), o, &ops)
+
+	if o.detectMoves {
+		ops = compactMoves(ops)
+	}
+
+	patches := make([]Patch, len(ops))
+	for i, op := range ops {
+		patches[i] = op.patch
+	}
+	return patches, nil
+}
+
+// DiffJSON computes a minimal RFC 6902 patch that transforms the JSON document a
+// into the JSON document b.
+func DiffJSON(a, b []byte, opts ...DiffOption) ([]Patch, error) {
+	var av, bv interface{}
+	if err := json.Unmarshal(a, &av); err != nil {
+		return nil, fmt.Errorf("jpatch: decoding a: %w", err)
+	}
+	if err := json.Unmarshal(b, &bv); err != nil {
+		return nil, fmt.Errorf("jpatch: decoding b: %w", err)
+	}
+	return Diff(av, bv, opts...)
+}
+
+func diffValues(path string, a, b interface{}, o *diffOptions, ops *[]diffOp) {
+	if o.ignorePaths[path] {
+		return
+	}
+
+	switch bv := b.(type) {
+	case map[string]interface{}:
+		av, ok := a.(map[string]interface{})
+		if !ok {
+			*ops = append(*ops, diffOp{patch: Patch{Op: Replace, Path: path, Value: b}, value: b})
+			return
+		}
+		diffObjects(path, av, bv, o, ops)
+	case []interface{}:
+		av, ok := a.([]interface{})
+		if !ok {
+			*ops = append(*ops, diffOp{patch: Patch{Op: Replace, Path: path, Value: b}, value: b})
+			return
+		}
+		diffArrays(path, av, bv, o, ops)
+	default:
+		if !reflect.DeepEqual(a, b) {
+			*ops = append(*ops, diffOp{patch: Patch{Op: Replace, Path: path, Value: b}, value: b})
+		}
+	}
+}
+
+func diffObjects(path string, a, b map[string]interface{}, o *diffOptions, ops *[]diffOp) {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+		seen[k] = true
+	}
+	for k := range b {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := path + "/" + escapeToken(k)
+		if o.ignorePaths[childPath] {
+			continue
+		}
+
+		av, aok := a[k]
+		bv, bok := b[k]
+
+		switch {
+		case aok && !bok:
+			*ops = append(*ops, diffOp{patch: Patch{Op: Remove, Path: childPath}, value: av})
+		case !aok && bok:
+			*ops = append(*ops, diffOp{patch: Patch{Op: Add, Path: childPath, Value: bv}, value: bv})
+		default:
+			diffValues(childPath, av, bv, o, ops)
+		}
+	}
+}
+
+// diffArrays emits a remove (in descending index order) for every element of a
+// not part of the longest common subsequence with b, followed by an add (in
+// ascending index order) for every element of b not part of it. Removing from
+// the end first keeps earlier indices valid as each operation is applied.
+func diffArrays(path string, a, b []interface{}, o *diffOptions, ops *[]diffOp) {
+	lcs := longestCommonSubsequence(a, b)
+
+	aMatched := make([]bool, len(a))
+	bMatched := make([]bool, len(b))
+	for _, pair := range lcs {
+		aMatched[pair[0]] = true
+		bMatched[pair[1]] = true
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if !aMatched[i] {
+			*ops = append(*ops, diffOp{patch: Patch{Op: Remove, Path: path + "/" + strconv.Itoa(i)}, value: a[i], arrayElement: true})
+		}
+	}
+
+	for i := range b {
+		if !bMatched[i] {
+			*ops = append(*ops, diffOp{patch: Patch{Op: Add, Path: path + "/" + strconv.Itoa(i), Value: b[i]}, value: b[i], arrayElement: true})
+		}
+	}
+}
+
+// longestCommonSubsequence returns index pairs (i, j) of elements shared, in
+// order, between a and b, using the standard O(len(a)*len(b)) LCS table.
+func longestCommonSubsequence(a, b []interface{}) [][2]int {
+	n, m := len(a), len(b)
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case reflect.DeepEqual(a[i], b[j]):
+				dp[i][j] = dp[i+1][j+1] + 1
+			case dp[i+1][j] >= dp[i][j+1]:
+				dp[i][j] = dp[i+1][j]
+			default:
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+
+	var pairs [][2]int
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case reflect.DeepEqual(a[i], b[j]):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// compactMoves merges a remove/add pair that carried the same value into a
+// single "move" operation, in place of the add. Array-index paths are never
+// collapsed: they're positional, so a remove/add's index shifts as sibling
+// elements are removed or added, and a "move" built from the original indices
+// would target the wrong elements once those siblings have shifted. Only
+// remove/add pairs addressing stable object-member paths are collapsed.
+func compactMoves(ops []diffOp) []diffOp {
+	matchedRemove := make(map[int]bool)
+	moves := make(map[int]Patch)
+	addUsed := make(map[int]bool)
+
+	for ri, r := range ops {
+		if r.patch.Op != Remove || r.arrayElement {
+			continue
+		}
+		for ai, a := range ops {
+			if a.patch.Op != Add || a.arrayElement || addUsed[ai] {
+				continue
+			}
+			if reflect.DeepEqual(r.value, a.value) {
+				matchedRemove[ri] = true
+				addUsed[ai] = true
+				moves[ai] = Patch{Op: Move, From: r.patch.Path, Path: a.patch.Path}
+				break
+			}
+		}
+	}
+
+	result := make([]diffOp, 0, len(ops))
+	for i, op := range ops {
+		if matchedRemove[i] {
+			continue
+		}
+		if mv, ok := moves[i]; ok {
+			result = append(result, diffOp{patch: mv})
+			continue
+		}
+		result = append(result, op)
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package jpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffScalarsAndObjects(t *testing.T) {
+	assert := assert.New(t)
+
+	a := []byte(`{"name":"alice","age":30}`)
+	b := []byte(`{"name":"bob","tags":["x"]}`)
+
+	patches, err := DiffJSON(a, b)
+	assert.Nil(err)
+
+	applied, err := Apply(a, patches)
+	assert.Nil(err)
+	assert.JSONEq(string(b), string(applied))
+}
+
+func TestDiffArrays(t *testing.T) {
+	assert := assert.New(t)
+
+	a := []byte(`{"items":["a","b","c"]}`)
+	b := []byte(`{"items":["a","c","d"]}`)
+
+	patches, err := DiffJSON(a, b)
+	assert.Nil(err)
+
+	applied, err := Apply(a, patches)
+	assert.Nil(err)
+	assert.JSONEq(string(b), string(applied))
+}
+
+func TestDiffDetectMoves(t *testing.T) {
+	assert := assert.New(t)
+
+	a := map[string]interface{}{"foo": map[string]interface{}{"v": float64(1)}}
+	b := map[string]interface{}{"bar": map[string]interface{}{"v": float64(1)}}
+
+	patches, err := Diff(a, b, WithDetectMoves(true))
+	assert.Nil(err)
+	assert.Equal([]Patch{{Op: Move, From: "/foo", Path: "/bar"}}, patches)
+}
+
+func TestDiffDetectMovesArrayRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	a := []byte(`{"items":[1,2,3,4]}`)
+	b := []byte(`{"items":[4,3,2,1]}`)
+
+	patches, err := DiffJSON(a, b, WithDetectMoves(true))
+	assert.Nil(err)
+
+	for _, p := range patches {
+		assert.NotEqual(Move, p.Op, "array elements must never be collapsed into a move: %+v", p)
+	}
+
+	applied, err := Apply(a, patches)
+	assert.Nil(err)
+	assert.JSONEq(string(b), string(applied))
+}
+
+func TestDiffIgnorePaths(t *testing.T) {
+	assert := assert.New(t)
+
+	a := map[string]interface{}{"name": "alice", "updatedAt": "t0"}
+	b := map[string]interface{}{"name": "bob", "updatedAt": "t1"}
+
+	patches, err := Diff(a, b, WithIgnorePaths([]string{"/updatedAt"}))
+	assert.Nil(err)
+	assert.Equal([]Patch{{Op: Replace, Path: "/name", Value: "bob"}}, patches)
+}
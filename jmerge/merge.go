@@ -0,0 +1,178 @@
+// Package jmerge implements JSON Merge Patch (RFC 7396) as a companion to
+// jpatch's RFC 6902 support.
+package jmerge
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sethjback/jpatch"
+)
+
+// MergePatch applies a JSON Merge Patch document to original and returns the
+// merged result. Per RFC 7396, object members set to null in patch are removed
+// from the result, other object members are merged recursively, and anything
+// else (arrays, scalars, or a non-object patch) replaces the original wholesale.
+func MergePatch(original, patch []byte) ([]byte, error) {
+	var patchVal interface{}
+	if err := json.Unmarshal(patch, &patchVal); err != nil {
+		return nil, fmt.Errorf("jmerge: decoding patch: %w", err)
+	}
+
+	var origVal interface{}
+	if err := json.Unmarshal(original, &origVal); err != nil {
+		return nil, fmt.Errorf("jmerge: decoding original: %w", err)
+	}
+
+	return json.Marshal(mergeValue(origVal, patchVal))
+}
+
+func mergeValue(original, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	origObj, _ := original.(map[string]interface{})
+
+	result := make(map[string]interface{}, len(origObj))
+	for k, v := range origObj {
+		result[k] = v
+	}
+
+	for k, v := range patchObj {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		result[k] = mergeValue(result[k], v)
+	}
+
+	return result
+}
+
+// CreateMergePatch computes a JSON Merge Patch document that, when applied to
+// original via MergePatch, produces modified.
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	var origVal interface{}
+	if err := json.Unmarshal(original, &origVal); err != nil {
+		return nil, fmt.Errorf("jmerge: decoding original: %w", err)
+	}
+
+	var modVal interface{}
+	if err := json.Unmarshal(modified, &modVal); err != nil {
+		return nil, fmt.Errorf("jmerge: decoding modified: %w", err)
+	}
+
+	return json.Marshal(diffValue(origVal, modVal))
+}
+
+func diffValue(original, modified interface{}) interface{} {
+	origObj, origIsObj := original.(map[string]interface{})
+	modObj, modIsObj := modified.(map[string]interface{})
+	if !origIsObj || !modIsObj {
+		return modified
+	}
+
+	patch := map[string]interface{}{}
+	for k, ov := range origObj {
+		mv, ok := modObj[k]
+		if !ok {
+			patch[k] = nil
+			continue
+		}
+		if !reflect.DeepEqual(ov, mv) {
+			patch[k] = diffValue(ov, mv)
+		}
+	}
+	for k, mv := range modObj {
+		if _, ok := origObj[k]; !ok {
+			patch[k] = mv
+		}
+	}
+
+	return patch
+}
+
+// FromMergePatch lowers a JSON Merge Patch document into an equivalent sequence
+// of RFC 6902 operations that, applied to original, produce the same result as
+// MergePatch(original, patch) — so the patch can flow through
+// jpatch.ProcessPatches and be validated against a Patchable's PathSegment
+// schema like any other patch. Null members become "remove" operations, and a
+// member is only lowered into per-field ops when original already has an
+// object at that path; otherwise (the member is new, or original's existing
+// value there isn't an object) it's lowered to a single whole-subtree "add",
+// built with mergeValue, so the op doesn't depend on a parent that may not
+// exist or may be the wrong type.
+//
+// This takes original deliberately, unlike a patch-only lowering: without it
+// there's no way to tell a member that should merge into an existing object
+// from one whose parent isn't an object at all, and no way to tell a null
+// member that should remove something from one whose target already doesn't
+// exist (a no-op under RFC 7396, but an error from jpatch's "remove").
+func FromMergePatch(original, patch []byte) ([]jpatch.Patch, error) {
+	var patchVal interface{}
+	if err := json.Unmarshal(patch, &patchVal); err != nil {
+		return nil, fmt.Errorf("jmerge: decoding patch: %w", err)
+	}
+
+	patchObj, ok := patchVal.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("jmerge: merge patch must be a JSON object, got %T", patchVal)
+	}
+
+	var origVal interface{}
+	if len(original) > 0 {
+		if err := json.Unmarshal(original, &origVal); err != nil {
+			return nil, fmt.Errorf("jmerge: decoding original: %w", err)
+		}
+	}
+	origObj, _ := origVal.(map[string]interface{})
+
+	return patchOps("", origObj, patchObj), nil
+}
+
+func patchOps(prefix string, original, patch map[string]interface{}) []jpatch.Patch {
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var ops []jpatch.Patch
+	for _, k := range keys {
+		path := prefix + "/" + escapeToken(k)
+		v := patch[k]
+		ov, existed := original[k]
+
+		switch {
+		case v == nil:
+			if existed {
+				ops = append(ops, jpatch.Patch{Op: jpatch.Remove, Path: path})
+			}
+		default:
+			nested, isObj := v.(map[string]interface{})
+			if !isObj {
+				ops = append(ops, jpatch.Patch{Op: jpatch.Add, Path: path, Value: v})
+				continue
+			}
+			if origNested, ok := ov.(map[string]interface{}); ok {
+				ops = append(ops, patchOps(path, origNested, nested)...)
+				continue
+			}
+			ops = append(ops, jpatch.Patch{Op: jpatch.Add, Path: path, Value: mergeValue(ov, nested)})
+		}
+	}
+
+	return ops
+}
+
+// escapeToken escapes a single reference token per RFC 6901 ("~" -> "~0", "/" -> "~1").
+func escapeToken(t string) string {
+	t = strings.ReplaceAll(t, "~", "~0")
+	t = strings.ReplaceAll(t, "/", "~1")
+	return t
+}
@@ -0,0 +1,96 @@
+package jmerge
+
+import (
+	"testing"
+
+	"github.com/sethjback/jpatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	original := []byte(`{"a":"b","c":{"d":"e","f":"g"}}`)
+	patch := []byte(`{"a":"z","c":{"f":null}}`)
+
+	out, err := MergePatch(original, patch)
+	assert.Nil(err)
+	assert.JSONEq(`{"a":"z","c":{"d":"e"}}`, string(out))
+}
+
+func TestMergePatchReplacesArraysAndScalars(t *testing.T) {
+	assert := assert.New(t)
+
+	original := []byte(`{"a":[1,2,3]}`)
+	patch := []byte(`{"a":[4,5]}`)
+
+	out, err := MergePatch(original, patch)
+	assert.Nil(err)
+	assert.JSONEq(`{"a":[4,5]}`, string(out))
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	original := []byte(`{"a":"b","c":{"d":"e","f":"g"}}`)
+	modified := []byte(`{"a":"z","c":{"d":"e"}}`)
+
+	patch, err := CreateMergePatch(original, modified)
+	assert.Nil(err)
+
+	merged, err := MergePatch(original, patch)
+	assert.Nil(err)
+	assert.JSONEq(string(modified), string(merged))
+}
+
+func TestFromMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	original := []byte(`{"a":"b","c":{"d":"e","f":"g"},"g/h":0}`)
+	ops, err := FromMergePatch(original, []byte(`{"a":"z","c":{"f":null},"g/h":1}`))
+	assert.Nil(err)
+
+	assert.Equal([]jpatch.Patch{
+		{Op: jpatch.Add, Path: "/a", Value: "z"},
+		{Op: jpatch.Remove, Path: "/c/f"},
+		{Op: jpatch.Add, Path: "/g~1h", Value: float64(1)},
+	}, ops)
+}
+
+func TestFromMergePatchWholeSubtreeWhenNotAnObject(t *testing.T) {
+	assert := assert.New(t)
+
+	original := []byte(`{"a":5}`)
+	ops, err := FromMergePatch(original, []byte(`{"a":{"b":1}}`))
+	assert.Nil(err)
+	assert.Equal([]jpatch.Patch{
+		{Op: jpatch.Add, Path: "/a", Value: map[string]interface{}{"b": float64(1)}},
+	}, ops)
+
+	applied, err := jpatch.Apply(original, ops)
+	assert.Nil(err)
+	assert.JSONEq(`{"a":{"b":1}}`, string(applied))
+}
+
+func TestFromMergePatchEmptyObject(t *testing.T) {
+	assert := assert.New(t)
+
+	original := []byte(`{}`)
+	ops, err := FromMergePatch(original, []byte(`{"a":{}}`))
+	assert.Nil(err)
+	assert.Equal([]jpatch.Patch{
+		{Op: jpatch.Add, Path: "/a", Value: map[string]interface{}{}},
+	}, ops)
+
+	applied, err := jpatch.Apply(original, ops)
+	assert.Nil(err)
+	assert.JSONEq(`{"a":{}}`, string(applied))
+}
+
+func TestFromMergePatchSkipsRemoveOfMissingMember(t *testing.T) {
+	assert := assert.New(t)
+
+	ops, err := FromMergePatch([]byte(`{}`), []byte(`{"a":null}`))
+	assert.Nil(err)
+	assert.Empty(ops)
+}
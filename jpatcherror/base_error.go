@@ -3,10 +3,13 @@ package jpatcherror
 import "fmt"
 
 type baseError struct {
-	message string
-	code    string
-	details string
-	origin  interface{}
+	message  string
+	code     string
+	details  string
+	origin   interface{}
+	path     string
+	index    int
+	hasIndex bool
 }
 
 func (e baseError) Message() string {
@@ -14,7 +17,7 @@ func (e baseError) Message() string {
 }
 
 func (e baseError) Code() string {
-	return e.details
+	return e.code
 }
 
 func (e baseError) Origin() interface{} {
@@ -25,12 +28,52 @@ func (e baseError) Details() string {
 	return e.details
 }
 
+func (e baseError) Path() string {
+	return e.path
+}
+
+func (e baseError) Index() (int, bool) {
+	return e.index, e.hasIndex
+}
+
+func (e baseError) WithPath(path string) Error {
+	e.path = path
+	return e
+}
+
+func (e baseError) WithIndex(index int) Error {
+	e.index = index
+	e.hasIndex = true
+	return e
+}
+
 func (e baseError) Error() string {
 	msg := e.message
 
+	if e.path != "" {
+		msg = fmt.Sprintf("%s [%s]", msg, e.path)
+	}
+
 	if e.details != "" {
 		msg = fmt.Sprintf("%s (%s)", msg, e.details)
 	}
 
 	return msg
 }
+
+// Is reports whether target is the sentinel error for e's Code(), so
+// errors.Is(err, jpatcherror.ErrInvalidPath) works regardless of Path/Index/Origin.
+func (e baseError) Is(target error) bool {
+	return codeSentinels[e.code] == target
+}
+
+// Unwrap exposes origin when it is itself an error (e.g. a lower-level error this
+// one wraps), so errors.Is/errors.As can continue down the chain. Origin is often
+// non-error context instead (such as the Patch that caused the failure), in which
+// case Unwrap returns nil.
+func (e baseError) Unwrap() error {
+	if err, ok := e.origin.(error); ok {
+		return err
+	}
+	return nil
+}
@@ -1,13 +1,40 @@
 package jpatcherror
 
+import "errors"
+
 // Error codes
 const (
 	ErrorInvalidOperation = "InvalidOperation"
 	ErrorInvalidPath      = "InvalidPath"
 	ErrorInvalidSegment   = "InvalidSegment"
 	ErrorInvalidValue     = "InvalidValue"
+	ErrorTestFailed       = "TestFailed"
+	ErrorMissing          = "Missing"
+	ErrorInvalidIndex     = "InvalidIndex"
+)
+
+// Sentinel errors, one per code, so callers can use errors.Is(err, jpatcherror.ErrInvalidPath)
+// instead of comparing Code() strings by hand.
+var (
+	ErrInvalidOperation = errors.New(ErrorInvalidOperation)
+	ErrInvalidPath      = errors.New(ErrorInvalidPath)
+	ErrInvalidSegment   = errors.New(ErrorInvalidSegment)
+	ErrInvalidValue     = errors.New(ErrorInvalidValue)
+	ErrTestFailed       = errors.New(ErrorTestFailed)
+	ErrMissing          = errors.New(ErrorMissing)
+	ErrInvalidIndex     = errors.New(ErrorInvalidIndex)
 )
 
+var codeSentinels = map[string]error{
+	ErrorInvalidOperation: ErrInvalidOperation,
+	ErrorInvalidPath:      ErrInvalidPath,
+	ErrorInvalidSegment:   ErrInvalidSegment,
+	ErrorInvalidValue:     ErrInvalidValue,
+	ErrorTestFailed:       ErrTestFailed,
+	ErrorMissing:          ErrMissing,
+	ErrorInvalidIndex:     ErrInvalidIndex,
+}
+
 // Error staisfies the built in error interface in addition to providing more detailed information about what went wrong
 type Error interface {
 	error
@@ -19,8 +46,25 @@ type Error interface {
 	Origin() interface{}
 
 	Details() string
+
+	// Path is the JSON pointer the error occurred at, if any.
+	Path() string
+
+	// Index is the index, within a patch set, of the patch the error occurred in.
+	// ok is false if the error isn't associated with a particular patch.
+	Index() (index int, ok bool)
+
+	// WithPath returns a copy of the error with Path set to path.
+	WithPath(path string) Error
+
+	// WithIndex returns a copy of the error with Index set to index.
+	WithIndex(index int) Error
+
+	// Unwrap exposes origin when it is itself an error, so errors.Is/errors.As can
+	// see through it.
+	Unwrap() error
 }
 
 func New(message, code, details string, origin interface{}) Error {
-	return baseError{message, code, details, origin}
+	return baseError{message: message, code: code, details: details, origin: origin}
 }
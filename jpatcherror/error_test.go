@@ -0,0 +1,56 @@
+package jpatcherror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeAndIs(t *testing.T) {
+	assert := assert.New(t)
+
+	err := New("bad path", ErrorInvalidPath, "details", nil)
+
+	assert.Equal(ErrorInvalidPath, err.Code())
+	assert.True(errors.Is(err, ErrInvalidPath))
+	assert.False(errors.Is(err, ErrInvalidValue))
+}
+
+func TestWithPathAndIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	err := New("bad value", ErrorInvalidValue, "", nil).WithPath("/a/b").WithIndex(2)
+
+	assert.Equal("/a/b", err.Path())
+	index, ok := err.Index()
+	assert.True(ok)
+	assert.Equal(2, index)
+}
+
+func TestUnwrapOrigin(t *testing.T) {
+	assert := assert.New(t)
+
+	cause := errors.New("underlying failure")
+
+	withErrorOrigin := New("wrapping", ErrorInvalidOperation, "", cause)
+	assert.True(errors.Is(withErrorOrigin, cause))
+
+	withValueOrigin := New("wrapping", ErrorInvalidOperation, "", "some context")
+	assert.Nil(errors.Unwrap(withValueOrigin))
+}
+
+func TestPatchErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	var patchErrs PatchErrors
+	patchErrs = append(patchErrs, New("bad path", ErrorInvalidPath, "", nil).WithIndex(0))
+	patchErrs = append(patchErrs, New("bad value", ErrorInvalidValue, "", nil).WithIndex(1))
+
+	var err error = patchErrs
+
+	var target PatchErrors
+	assert.True(errors.As(err, &target))
+	assert.Equal(2, len(target))
+	assert.True(errors.Is(err, ErrInvalidValue))
+}
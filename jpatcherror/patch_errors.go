@@ -0,0 +1,31 @@
+package jpatcherror
+
+import "strings"
+
+// PatchErrors aggregates the failures found while validating a set of patches,
+// e.g. from jpatch.ProcessPatches. It implements error and, per Go 1.20,
+// Unwrap() []error, so callers can do:
+//
+//	var patchErrs jpatcherror.PatchErrors
+//	if errors.As(err, &patchErrs) {
+//		for _, e := range patchErrs {
+//			// each e is a jpatcherror.Error with its own Path()/Index()
+//		}
+//	}
+type PatchErrors []error
+
+func (p PatchErrors) Error() string {
+	if len(p) == 1 {
+		return p[0].Error()
+	}
+
+	msgs := make([]string, len(p))
+	for i, e := range p {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (p PatchErrors) Unwrap() []error {
+	return []error(p)
+}
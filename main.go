@@ -121,9 +121,9 @@ type Patchable interface {
 }
 
 // ProcessPatches process patch objects
-func ProcessPatches(patches []Patch, pable Patchable) ([]Patch, []error) {
+func ProcessPatches(patches []Patch, pable Patchable) ([]Patch, error) {
 
-	var errs []error
+	var errs jpatcherror.PatchErrors
 	rootSegment := pable.GetJPatchRootSegment()
 
 	vAdd := make([]Patch, 0)
@@ -131,23 +131,23 @@ func ProcessPatches(patches []Patch, pable Patchable) ([]Patch, []error) {
 	vMove := make([]Patch, 0)
 	vReplace := make([]Patch, 0)
 
-	for _, p := range patches {
+	for i, p := range patches {
 		err := validatePatch(p)
 		if err != nil {
-			errs = append(errs, err)
+			errs = append(errs, withPatchContext(err, p.Path, i))
 			continue
 		}
 
 		finalPath, err := validatePath(p.Path, p.Op, rootSegment)
 		if err != nil {
-			errs = append(errs, err)
+			errs = append(errs, withPatchContext(err, p.Path, i))
 		}
 		p.Path = finalPath
 
 		if p.From != "" {
 			finalPath, err = validateFrom(p.From, p.Op, rootSegment)
 			if err != nil {
-				errs = append(errs, err)
+				errs = append(errs, withPatchContext(err, p.From, i))
 			}
 			p.From = finalPath
 		}
@@ -172,7 +172,21 @@ func ProcessPatches(patches []Patch, pable Patchable) ([]Patch, []error) {
 		return nil, errs
 	}
 
-	return pable.ValidateJPatchPatches(vPatches)
+	validated, verrs := pable.ValidateJPatchPatches(vPatches)
+	if len(verrs) != 0 {
+		return nil, jpatcherror.PatchErrors(verrs)
+	}
+
+	return validated, nil
+}
+
+// withPatchContext attaches the patch's path and index within the patch set to
+// err, if err is a jpatcherror.Error.
+func withPatchContext(err error, path string, index int) error {
+	if je, ok := err.(jpatcherror.Error); ok {
+		return je.WithPath(path).WithIndex(index)
+	}
+	return err
 }
 
 func validatePath(path, op string, root *PathSegment) (string, error) {
@@ -235,7 +249,18 @@ func traceObjectPathString(path string, op string, root *PathSegment) (string, *
 		}
 
 		currentSegment = nextSeg
-		lastPath = pathValue
+
+		// The "-" pseudo-segment has no PathValue of its own (it's a stand-in for
+		// "the next index of the array"), so keep the array segment's own
+		// PathValue as lastPath instead of overwriting it with "-"'s empty one -
+		// that's what validatePath checks allowed operations against.
+		// Since lastPath never holds "-", the operations that cannot target it
+		// are rejected here.
+		if pathValue.Name != "-" {
+			lastPath = pathValue
+		} else if op == Remove || op == Test || op == Replace {
+			return "", nil, jpatcherror.New("Invalid Operation", jpatcherror.ErrorInvalidOperation, "cannot "+op+" array index of '-'", nil)
+		}
 
 		finalPath += "/" + pathValue.Name
 	}
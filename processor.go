@@ -0,0 +1,257 @@
+package jpatch
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ValidatedPatch is a Patch that has passed validation against a Patchable's
+// PathSegment schema, carrying its position in the original input stream.
+type ValidatedPatch struct {
+	Patch
+	// Index is the patch's position in the stream Process read it from.
+	Index int
+}
+
+// ProcessorOption configures a Processor.
+type ProcessorOption func(*processorOptions)
+
+type processorOptions struct {
+	batchSize     int
+	flushInterval time.Duration
+	observer      func(op string, dur time.Duration)
+}
+
+// WithBatchSize sets how many patches a Processor accumulates before validating
+// and ordering them as a window. The default is 100.
+func WithBatchSize(n int) ProcessorOption {
+	return func(o *processorOptions) { o.batchSize = n }
+}
+
+// WithFlushInterval bounds how long a Processor waits for a window to fill
+// before validating whatever it has. The default is 100ms.
+func WithFlushInterval(d time.Duration) ProcessorOption {
+	return func(o *processorOptions) { o.flushInterval = d }
+}
+
+// WithObserver registers a callback invoked after each patch is validated, with
+// the operation and how long validation took, for instrumenting latency per op
+// type.
+func WithObserver(observer func(op string, dur time.Duration)) ProcessorOption {
+	return func(o *processorOptions) { o.observer = observer }
+}
+
+// Processor validates patches as they arrive instead of requiring the whole
+// patch set up front, for patch documents too large to comfortably buffer in
+// memory all at once.
+type Processor struct {
+	pable Patchable
+	opts  processorOptions
+}
+
+// NewProcessor creates a Processor that validates patches against pable.
+func NewProcessor(pable Patchable, opts ...ProcessorOption) *Processor {
+	o := processorOptions{batchSize: 100, flushInterval: 100 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Processor{pable: pable, opts: o}
+}
+
+// Process validates patches read from in as they arrive, in windows bounded by
+// WithBatchSize/WithFlushInterval, applying the same remove->replace->move->add
+// ordering ProcessPatches uses within each window before handing it to the
+// Patchable's ValidateJPatchPatches. It closes both returned channels once in is
+// closed or ctx is done.
+func (p *Processor) Process(ctx context.Context, in <-chan Patch) (<-chan ValidatedPatch, <-chan error) {
+	out := make(chan ValidatedPatch)
+	errs := make(chan error)
+
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		rootSegment := p.pable.GetJPatchRootSegment()
+		index := 0
+		batch := make([]Patch, 0, p.opts.batchSize)
+		indices := make([]int, 0, p.opts.batchSize)
+
+		timer := time.NewTimer(p.opts.flushInterval)
+		defer timer.Stop()
+
+		flush := func() {
+			if len(batch) == 0 {
+				return
+			}
+			p.validateWindow(ctx, batch, indices, rootSegment, out, errs)
+			batch = batch[:0]
+			indices = indices[:0]
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case patch, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, patch)
+				indices = append(indices, index)
+				index++
+				if len(batch) >= p.opts.batchSize {
+					flush()
+					if !timer.Stop() {
+						<-timer.C
+					}
+					timer.Reset(p.opts.flushInterval)
+				}
+			case <-timer.C:
+				flush()
+				timer.Reset(p.opts.flushInterval)
+			}
+		}
+	}()
+
+	return out, errs
+}
+
+// validateWindow validates one window of a patch stream: each patch is checked
+// against rootSegment (as ProcessPatches does), the survivors are reordered
+// remove->replace->move->add, and that ordered slice is handed to the
+// Patchable's ValidateJPatchPatches as a single call.
+func (p *Processor) validateWindow(ctx context.Context, batch []Patch, indices []int, rootSegment *PathSegment, out chan<- ValidatedPatch, errs chan<- error) {
+	type indexed struct {
+		patch Patch
+		index int
+	}
+
+	var vAdd, vRemove, vMove, vReplace []indexed
+
+	for i, patch := range batch {
+		start := time.Now()
+		err := validateAgainstSegment(&patch, rootSegment)
+		if p.opts.observer != nil {
+			p.opts.observer(patch.Op, time.Since(start))
+		}
+		if err != nil {
+			select {
+			case errs <- withPatchContext(err, patch.Path, indices[i]):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		item := indexed{patch, indices[i]}
+		switch patch.Op {
+		case Add:
+			vAdd = append(vAdd, item)
+		case Move:
+			vMove = append(vMove, item)
+		case Remove:
+			vRemove = append(vRemove, item)
+		case Replace:
+			vReplace = append(vReplace, item)
+		}
+	}
+
+	ordered := make([]indexed, 0, len(vRemove)+len(vReplace)+len(vMove)+len(vAdd))
+	ordered = append(ordered, vRemove...)
+	ordered = append(ordered, vReplace...)
+	ordered = append(ordered, vMove...)
+	ordered = append(ordered, vAdd...)
+	if len(ordered) == 0 {
+		return
+	}
+
+	vPatches := make([]Patch, len(ordered))
+	for i, item := range ordered {
+		vPatches[i] = item.patch
+	}
+
+	validated, verrs := p.pable.ValidateJPatchPatches(vPatches)
+	if len(verrs) != 0 {
+		for _, verr := range verrs {
+			select {
+			case errs <- verr:
+			case <-ctx.Done():
+				return
+			}
+		}
+		return
+	}
+
+	for i := 0; i < len(validated) && i < len(ordered); i++ {
+		select {
+		case out <- ValidatedPatch{Patch: validated[i], Index: ordered[i].index}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// validateAgainstSegment runs the same per-patch checks ProcessPatches does
+// (validatePatch, then validatePath/validateFrom against root), rewriting
+// patch.Path/patch.From to their translated form in place.
+func validateAgainstSegment(patch *Patch, root *PathSegment) error {
+	if err := validatePatch(*patch); err != nil {
+		return err
+	}
+
+	finalPath, err := validatePath(patch.Path, patch.Op, root)
+	if err != nil {
+		return err
+	}
+	patch.Path = finalPath
+
+	if patch.From != "" {
+		finalFrom, err := validateFrom(patch.From, patch.Op, root)
+		if err != nil {
+			return err
+		}
+		patch.From = finalFrom
+	}
+
+	return nil
+}
+
+// ApplyStreaming applies patches read from in to doc as they arrive, instead of
+// requiring the whole patch set as a single slice up front, so a document with
+// thousands of operations doesn't need them all materialized in memory at once.
+// Semantics otherwise match Apply, including atomic rollback: if any operation
+// fails, doc is left unaffected and the partially-applied result is discarded.
+func ApplyStreaming(ctx context.Context, doc []byte, in <-chan Patch, opts ...ApplyOption) ([]byte, error) {
+	v, err := decodeJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jpatch: decoding document: %w", err)
+	}
+
+	o := &applyOptions{accumulatedCopySizeLimit: -1}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	root := deepCopy(v)
+	var copiedBytes int64
+	index := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case p, ok := <-in:
+			if !ok {
+				return encodeJSON(root)
+			}
+			next, err := applyOne(root, p, o, &copiedBytes)
+			if err != nil {
+				return nil, fmt.Errorf("jpatch: patch %d (%s %s): %w", index, p.Op, p.Path, err)
+			}
+			root = next
+			index++
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package jpatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type passthroughPatchable struct {
+	root *PathSegment
+}
+
+func (p *passthroughPatchable) GetJPatchRootSegment() *PathSegment {
+	return p.root
+}
+
+func (p *passthroughPatchable) ValidateJPatchPatches(patches []Patch) ([]Patch, []error) {
+	return patches, nil
+}
+
+func newPassthroughPatchable() *passthroughPatchable {
+	root := &PathSegment{Wildcard: true}
+	root.AddValue("*", "*", Add, Remove, Replace, Move, Copy, Test)
+	return &passthroughPatchable{root: root}
+}
+
+func TestProcessorValidatesStream(t *testing.T) {
+	assert := assert.New(t)
+
+	proc := NewProcessor(newPassthroughPatchable(), WithBatchSize(2), WithFlushInterval(20*time.Millisecond))
+
+	in := make(chan Patch)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	out, errs := proc.Process(ctx, in)
+
+	go func() {
+		in <- Patch{Op: Add, Path: "/a", Value: 1}
+		in <- Patch{Op: Remove, Path: "/b"}
+		close(in)
+	}()
+
+	var got []ValidatedPatch
+	for out != nil || errs != nil {
+		select {
+		case vp, ok := <-out:
+			if !ok {
+				out = nil
+				continue
+			}
+			got = append(got, vp)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	assert.Equal(2, len(got))
+	// remove->add ordering is preserved within the window.
+	assert.Equal(Remove, got[0].Op)
+	assert.Equal(Add, got[1].Op)
+}
+
+func TestApplyStreaming(t *testing.T) {
+	assert := assert.New(t)
+
+	in := make(chan Patch)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	go func() {
+		in <- Patch{Op: Add, Path: "/a", Value: 1}
+		in <- Patch{Op: Add, Path: "/b", Value: 2}
+		close(in)
+	}()
+
+	out, err := ApplyStreaming(ctx, []byte(`{}`), in)
+	assert.Nil(err)
+	assert.JSONEq(`{"a":1,"b":2}`, string(out))
+}
+
+func TestApplyStreamingStopsOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	in := make(chan Patch, 2)
+	in <- Patch{Op: Remove, Path: "/missing"}
+	close(in)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := ApplyStreaming(ctx, []byte(`{}`), in)
+	assert.NotNil(err)
+}
@@ -0,0 +1,217 @@
+package jpatch
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/sethjback/jpatch/jpatcherror"
+)
+
+// templateSegment is one "/"-separated component of a compiled path template.
+type templateSegment struct {
+	// literal is the exact value expected at this segment. Empty when varName is set.
+	literal string
+	// varName is the name this segment's value is captured under, or "" if this
+	// is a literal segment.
+	varName string
+	// greedy marks a {name=**} catch-all: it must be the final segment and
+	// captures every remaining segment, joined by "/", as a single value.
+	greedy bool
+}
+
+// PathTemplate is a compiled gRPC-gateway-style path template. It can be used
+// wherever a Patchable needs a PathSegment tree (via GetJPatchRootSegment), and
+// additionally captures named path variables out of a matching patch path.
+type PathTemplate struct {
+	segments []templateSegment
+	root     *PathSegment
+}
+
+// CompileTemplate compiles a path template such as
+// "/users/{userID}/orders/{orderID=*}/items/{itemPath=**}" into a PathTemplate.
+//
+// Supported captures are a bare "{name}" or explicit single-segment "{name=*}",
+// both of which match exactly one path segment, and "{name=**}", a catch-all
+// that matches one or more remaining segments and is only permitted as the
+// template's final component.
+func CompileTemplate(pattern string) (*PathTemplate, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, jpatcherror.New("Invalid Template", jpatcherror.ErrorInvalidPath, "template must begin with /", pattern)
+	}
+
+	parts := strings.Split(pattern, "/")[1:]
+	segments := make([]templateSegment, len(parts))
+
+	for i, part := range parts {
+		seg, err := compileTemplateSegment(part)
+		if err != nil {
+			return nil, err
+		}
+		if seg.greedy && i != len(parts)-1 {
+			return nil, jpatcherror.New("Invalid Template", jpatcherror.ErrorInvalidPath, "** capture must be the final path segment", pattern)
+		}
+		segments[i] = seg
+	}
+
+	return &PathTemplate{segments: segments, root: buildTemplateRoot(segments)}, nil
+}
+
+func compileTemplateSegment(part string) (templateSegment, error) {
+	if !strings.HasPrefix(part, "{") {
+		return templateSegment{literal: part}, nil
+	}
+
+	if !strings.HasSuffix(part, "}") {
+		return templateSegment{}, jpatcherror.New("Invalid Template", jpatcherror.ErrorInvalidPath, "unterminated capture: "+part, nil)
+	}
+
+	body := part[1 : len(part)-1]
+	name, pattern, hasPattern := strings.Cut(body, "=")
+	if name == "" {
+		return templateSegment{}, jpatcherror.New("Invalid Template", jpatcherror.ErrorInvalidPath, "capture is missing a name: "+part, nil)
+	}
+
+	if !hasPattern || pattern == "*" {
+		return templateSegment{varName: name}, nil
+	}
+	if pattern == "**" {
+		return templateSegment{varName: name, greedy: true}, nil
+	}
+
+	return templateSegment{}, jpatcherror.New("Invalid Template", jpatcherror.ErrorInvalidPath, "unsupported capture pattern: "+part, nil)
+}
+
+// templateSupportedOps is used as the SupportedOps for every segment a template
+// produces: a PathTemplate only routes and captures variables, it leaves
+// operation-level gating to the Patchable's own ValidateJPatchPatches/
+// TranslateJPatchPatches.
+var templateSupportedOps = []string{Add, Remove, Replace, Move, Copy, Test}
+
+// buildTemplateRoot builds the PathSegment tree that ProcessPatches validates
+// against. A greedy {name=**} segment is represented as a required wildcard
+// whose child is an optional wildcard that points back at itself, allowing it
+// to match one or more remaining path segments.
+func buildTemplateRoot(segments []templateSegment) *PathSegment {
+	root := &PathSegment{Optional: false}
+	current := root
+
+	for i, seg := range segments {
+		last := i == len(segments)-1
+
+		if seg.greedy {
+			// A greedy capture requires one segment; its optional child points
+			// back at itself, so it can match any further remaining segments.
+			tail := &PathSegment{Optional: true, Wildcard: true}
+			tail.AddValue("*", seg.varName, templateSupportedOps...)
+			tail.Children = map[string]*PathSegment{"*": tail}
+			current.Wildcard = true
+			current.AddValue("*", seg.varName, templateSupportedOps...)
+			current.AddChild("*", tail)
+			return root
+		}
+
+		if seg.varName != "" {
+			current.Wildcard = true
+			current.AddValue("*", seg.varName, templateSupportedOps...)
+			if last {
+				break
+			}
+			child := &PathSegment{Optional: false}
+			current.AddChild("*", child)
+			current = child
+			continue
+		}
+
+		current.AddValue(seg.literal, seg.literal, templateSupportedOps...)
+		if last {
+			break
+		}
+
+		child := &PathSegment{Optional: false}
+		current.AddChild(seg.literal, child)
+		current = child
+	}
+
+	return root
+}
+
+// GetJPatchRootSegment implements Patchable, returning the PathSegment tree
+// equivalent to the compiled template.
+func (t *PathTemplate) GetJPatchRootSegment() *PathSegment {
+	return t.root
+}
+
+// Vars matches path (an RFC 6901 pointer, e.g. "/users/42/orders/7") against the
+// template and, on success, returns the captured path variables.
+func (t *PathTemplate) Vars(path string) (map[string]string, bool) {
+	tokens, err := pointerTokens(path)
+	if err != nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string)
+
+	for i, seg := range t.segments {
+		if i >= len(tokens) {
+			return nil, false
+		}
+
+		if seg.greedy {
+			vars[seg.varName] = strings.Join(tokens[i:], "/")
+			return vars, true
+		}
+
+		if seg.varName != "" {
+			vars[seg.varName] = tokens[i]
+			continue
+		}
+
+		if seg.literal != tokens[i] {
+			return nil, false
+		}
+	}
+
+	if len(tokens) != len(t.segments) {
+		return nil, false
+	}
+
+	return vars, true
+}
+
+// TemplatePatchable extends Patchable for resources whose path is described by a
+// PathTemplate. Once a patch's path has matched the template, TranslateJPatchPatches
+// receives the validated patches alongside the path variables captured from them.
+type TemplatePatchable interface {
+	Patchable
+
+	// TranslateJPatchPatches receives the patches validated against
+	// GetJPatchRootSegment() along with the path variables captured by the
+	// PathTemplate that routed them here, e.g. {"userID": "42", "orderID": "7"}.
+	TranslateJPatchPatches(patches []Patch, vars map[string]string) ([]Patch, []error)
+}
+
+// ProcessTemplatePatches matches the first patch's path against tmpl, validates
+// all patches against pable as ProcessPatches does, then hands the validated
+// patches to pable.TranslateJPatchPatches along with the path variables captured
+// from that first path. The paths of the other patches are not matched against tmpl.
+func ProcessTemplatePatches(patches []Patch, tmpl *PathTemplate, pable TemplatePatchable) ([]Patch, []error) {
+	if len(patches) == 0 {
+		return pable.TranslateJPatchPatches(nil, map[string]string{})
+	}
+
+	vars, ok := tmpl.Vars(patches[0].Path)
+	if !ok {
+		return nil, []error{jpatcherror.New("Invalid Path", jpatcherror.ErrorInvalidPath, "path does not match template", patches[0])}
+	}
+
+	validated, err := ProcessPatches(patches, pable)
+	if err != nil {
+		var patchErrs jpatcherror.PatchErrors
+		if errors.As(err, &patchErrs) {
+			return nil, patchErrs
+		}
+		return nil, []error{err}
+	}
+
+	return pable.TranslateJPatchPatches(validated, vars)
+}
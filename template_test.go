@@ -0,0 +1,65 @@
+package jpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileTemplateVars(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl, err := CompileTemplate("/users/{userID}/orders/{orderID=*}/items/{itemPath=**}")
+	assert.Nil(err)
+
+	vars, ok := tmpl.Vars("/users/42/orders/7/items/a/b/c")
+	assert.True(ok)
+	assert.Equal(map[string]string{"userID": "42", "orderID": "7", "itemPath": "a/b/c"}, vars)
+
+	_, ok = tmpl.Vars("/users/42/orders/7")
+	assert.False(ok)
+
+	_, ok = tmpl.Vars("/users/42/carts/7/items/a")
+	assert.False(ok)
+}
+
+func TestCompileTemplateRejectsMisplacedGreedy(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := CompileTemplate("/a/{rest=**}/b")
+	assert.NotNil(err)
+}
+
+type fakeTemplateResource struct {
+	gotPatches []Patch
+	gotVars    map[string]string
+}
+
+func (f *fakeTemplateResource) GetJPatchRootSegment() *PathSegment {
+	tmpl, _ := CompileTemplate("/users/{userID}/name")
+	return tmpl.GetJPatchRootSegment()
+}
+
+func (f *fakeTemplateResource) ValidateJPatchPatches(patches []Patch) ([]Patch, []error) {
+	return patches, nil
+}
+
+func (f *fakeTemplateResource) TranslateJPatchPatches(patches []Patch, vars map[string]string) ([]Patch, []error) {
+	f.gotPatches = patches
+	f.gotVars = vars
+	return patches, nil
+}
+
+func TestProcessTemplatePatches(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl, err := CompileTemplate("/users/{userID}/name")
+	assert.Nil(err)
+
+	resource := &fakeTemplateResource{}
+	patches, errs := ProcessTemplatePatches([]Patch{{Op: Replace, Path: "/users/42/name", Value: "bob"}}, tmpl, resource)
+
+	assert.Nil(errs)
+	assert.Equal("42", resource.gotVars["userID"])
+	assert.Equal(1, len(patches))
+}